@@ -0,0 +1,258 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"jtso/logger"
+	"os/exec"
+	"strings"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/network"
+	"github.com/moby/moby/client"
+)
+
+// Docker Compose groups its containers with these well-known labels.
+const (
+	composeProjectLabel = "com.docker.compose.project"
+	composeServiceLabel = "com.docker.compose.service"
+)
+
+// ComposeService is one container grouped under a ComposeProject, labeled
+// with its compose service name.
+type ComposeService struct {
+	Service   string
+	Container container.Summary
+}
+
+// ComposeProject groups the containers belonging to the same compose project.
+type ComposeProject struct {
+	Name     string
+	Services []ComposeService
+}
+
+// ListComposeProjects groups containers by their compose project/service
+// labels; containers without a project label are omitted, and a container
+// with a project label but no service label is listed with an empty Service.
+func (m *Manager) ListComposeProjects() ([]ComposeProject, error) {
+	containers, err := m.cli.ContainerList(context.Background(), client.ContainerListOptions{All: true})
+	if err != nil {
+		logger.Log.Errorf("Unable to list containers for compose grouping: %v", err)
+		return nil, err
+	}
+
+	projects := make(map[string]*ComposeProject)
+	var order []string
+
+	for _, summary := range containers.Items {
+		project, ok := summary.Labels[composeProjectLabel]
+		if !ok {
+			continue
+		}
+		if _, exists := projects[project]; !exists {
+			projects[project] = &ComposeProject{Name: project}
+			order = append(order, project)
+		}
+		service := ComposeService{Service: summary.Labels[composeServiceLabel], Container: summary}
+		projects[project].Services = append(projects[project].Services, service)
+	}
+
+	result := make([]ComposeProject, 0, len(order))
+	for _, name := range order {
+		result = append(result, *projects[name])
+	}
+	return result, nil
+}
+
+func ListComposeProjects() ([]ComposeProject, error) {
+	if DefaultManager == nil {
+		logger.Log.Errorf("Unable to list compose projects: %v", errNoDockerClient)
+		return nil, errNoDockerClient
+	}
+	return DefaultManager.ListComposeProjects()
+}
+
+// runComposeCommand shells out to the `docker compose` CLI plugin, since the
+// Docker engine API itself has no compose-aware endpoints.
+func runComposeCommand(path, project string, args ...string) error {
+	cmdArgs := []string{"compose"}
+	if path != "" {
+		cmdArgs = append(cmdArgs, "-f", path)
+	}
+	if project != "" {
+		cmdArgs = append(cmdArgs, "-p", project)
+	}
+	cmdArgs = append(cmdArgs, args...)
+
+	output, err := exec.Command("docker", cmdArgs...).CombinedOutput()
+	if err != nil {
+		logger.Log.Errorf("docker %s failed: %v - %s", strings.Join(cmdArgs, " "), err, string(output))
+		return err
+	}
+	logger.Log.Infof("docker %s: %s", strings.Join(cmdArgs, " "), string(output))
+	return nil
+}
+
+// ComposeUp brings up the compose project defined in the file at path.
+func ComposeUp(path string) error {
+	return runComposeCommand(path, "", "up", "-d")
+}
+
+// ComposeDown tears down the named compose project.
+func ComposeDown(name string) error {
+	return runComposeCommand("", name, "down")
+}
+
+// ComposeRestart restarts every service of the named compose project.
+func ComposeRestart(name string) error {
+	return runComposeCommand("", name, "restart")
+}
+
+// RestartComposeProject restarts every container belonging to the named
+// compose project, the project-wide equivalent of RestartContainer.
+func (m *Manager) RestartComposeProject(project string) error {
+	services, err := m.composeProjectServices(project)
+	if err != nil {
+		return err
+	}
+
+	for _, service := range services {
+		name := strings.TrimPrefix(service.Container.Names[0], "/")
+		if err := m.RestartContainer(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func RestartComposeProject(project string) error {
+	if DefaultManager == nil {
+		logger.Log.Errorf("Unable to restart compose project %s: %v", project, errNoDockerClient)
+		return errNoDockerClient
+	}
+	return DefaultManager.RestartComposeProject(project)
+}
+
+// StopComposeProject stops every container belonging to the named compose
+// project, the project-wide equivalent of StopContainer.
+func (m *Manager) StopComposeProject(project string) error {
+	services, err := m.composeProjectServices(project)
+	if err != nil {
+		return err
+	}
+
+	for _, service := range services {
+		name := strings.TrimPrefix(service.Container.Names[0], "/")
+		m.StopContainer(name)
+	}
+	return nil
+}
+
+func StopComposeProject(project string) error {
+	if DefaultManager == nil {
+		logger.Log.Errorf("Unable to stop compose project %s: %v", project, errNoDockerClient)
+		return errNoDockerClient
+	}
+	return DefaultManager.StopComposeProject(project)
+}
+
+// composeProjectServices returns the containers tagged with the given
+// compose project name.
+func (m *Manager) composeProjectServices(project string) ([]ComposeService, error) {
+	projects, err := m.ListComposeProjects()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range projects {
+		if p.Name == project {
+			return p.Services, nil
+		}
+	}
+	return nil, fmt.Errorf("compose project '%s' not found", project)
+}
+
+// UpgradeContainer pulls newImage, then stops, removes and recreates the
+// container under the same name with its original config, networks and
+// labels, and starts it.
+func (m *Manager) UpgradeContainer(name, newImage string) error {
+	ctx := context.Background()
+
+	inspect, err := m.cli.ContainerInspect(ctx, name)
+	if err != nil {
+		logger.Log.Errorf("Unable to inspect %s container: %v", name, err)
+		return err
+	}
+
+	logger.Log.Infof("Pulling %s for %s upgrade", newImage, name)
+	pullReader, err := m.cli.ImagePull(ctx, newImage, client.ImagePullOptions{})
+	if err != nil {
+		logger.Log.Errorf("Unable to pull %s: %v", newImage, err)
+		return err
+	}
+	if _, err := io.Copy(io.Discard, pullReader); err != nil {
+		logger.Log.Errorf("Error reading image pull progress for %s: %v", newImage, err)
+	}
+	pullReader.Close()
+
+	timeout := 30
+	if _, err := m.cli.ContainerStop(ctx, name, client.ContainerStopOptions{Signal: "SIGTERM", Timeout: &timeout}); err != nil {
+		logger.Log.Errorf("Unable to stop %s container before upgrade: %v", name, err)
+		return err
+	}
+
+	// Force the remove: a container with an "always" restart policy can get
+	// auto-restarted by the daemon between the Stop above and this Remove.
+	if err := m.cli.ContainerRemove(ctx, name, client.ContainerRemoveOptions{Force: true}); err != nil {
+		logger.Log.Errorf("Unable to remove %s container before upgrade: %v", name, err)
+		return err
+	}
+
+	config := inspect.Config
+	config.Image = newImage
+
+	// The engine API only accepts a single network at creation time, so
+	// create on one network and attach the rest with NetworkConnect, the way
+	// docker-compose itself does it.
+	networkingConfig := &network.NetworkingConfig{}
+	var extraNetworks map[string]*network.EndpointSettings
+	for netName, endpoint := range inspect.NetworkSettings.Networks {
+		if networkingConfig.EndpointsConfig == nil {
+			networkingConfig.EndpointsConfig = map[string]*network.EndpointSettings{netName: endpoint}
+			continue
+		}
+		if extraNetworks == nil {
+			extraNetworks = make(map[string]*network.EndpointSettings)
+		}
+		extraNetworks[netName] = endpoint
+	}
+
+	created, err := m.cli.ContainerCreate(ctx, config, inspect.HostConfig, networkingConfig, nil, name)
+	if err != nil {
+		logger.Log.Errorf("Unable to recreate %s container with image %s: %v", name, newImage, err)
+		return err
+	}
+
+	for netName, endpoint := range extraNetworks {
+		if err := m.cli.NetworkConnect(ctx, netName, created.ID, endpoint); err != nil {
+			logger.Log.Errorf("Unable to attach %s container to network %s: %v", name, netName, err)
+			return err
+		}
+	}
+
+	if err := m.cli.ContainerStart(ctx, created.ID, client.ContainerStartOptions{}); err != nil {
+		logger.Log.Errorf("Unable to start upgraded %s container: %v", name, err)
+		return err
+	}
+
+	logger.Log.Infof("%s container upgraded to %s", name, newImage)
+	return nil
+}
+
+func UpgradeContainer(name, newImage string) error {
+	if DefaultManager == nil {
+		logger.Log.Errorf("Unable to upgrade %s container: %v", name, errNoDockerClient)
+		return errNoDockerClient
+	}
+	return DefaultManager.UpgradeContainer(name, newImage)
+}
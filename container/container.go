@@ -1,34 +1,137 @@
 package container
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
 	"jtso/logger"
-	"maps"
-	"slices"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/events"
+	"github.com/moby/moby/api/types/filters"
 	"github.com/moby/moby/client"
 )
 
+// statsHistorySize caps how many samples are kept per container.
+const statsHistorySize = 60
+
+// StatSample is one point in a container's stats history.
+type StatSample struct {
+	Timestamp time.Time
+	Cpu       float64
+	Mem       float64
+	NetRx     float64
+	NetTx     float64
+	BlkRead   float64
+	BlkWrite  float64
+}
+
 type ContainerStats struct {
 	Interval int
 	Stats    map[string]map[string]float64
+	History  map[string][]StatSample
 	StMu     *sync.Mutex
+
+	// streams and generation track the streamContainerStats goroutine
+	// currently responsible for each container name, shared between
+	// StartStatsStream (boot-time) and WatchContainerLifecycle (runtime
+	// start/unpause/die/stop/destroy), both guarded by StMu.
+	streams    map[string]*streamHandle
+	generation int64
+}
+
+// streamHandle identifies a single streamContainerStats goroutine. A
+// goroutine checks its own generation against the handle stored under its
+// container name before every write, so a stale goroutine (cancelled, or
+// superseded by a newer start) can never resurrect an evicted entry or
+// clobber a newer one's samples.
+type streamHandle struct {
+	generation int64
+	cancel     context.CancelFunc
 }
 
 var Cstats *ContainerStats
 
+// dockerAPIVersion pins the API version the client uses instead of
+// negotiating one with the daemon, so it cannot silently change if the
+// daemon is upgraded underneath jtso.
+const dockerAPIVersion = "1.45"
+
+// Manager owns a single Docker client shared across the package.
+type Manager struct {
+	cli *client.Client
+}
+
+// DefaultManager is the shared Manager used by the free functions below, for
+// backwards compatibility with existing callers.
+var DefaultManager *Manager
+
+// errNoDockerClient is returned by the free-function shims when Init failed
+// to open a Docker session, leaving DefaultManager nil.
+var errNoDockerClient = fmt.Errorf("docker client not initialized")
+
+// NewManager opens a single Docker client pinned to dockerAPIVersion.
+func NewManager() (*Manager, error) {
+	cli, err := client.New(client.FromEnv, client.WithVersion(dockerAPIVersion))
+	if err != nil {
+		return nil, err
+	}
+	return &Manager{cli: cli}, nil
+}
+
+// Close releases the underlying Docker client.
+func (m *Manager) Close() error {
+	return m.cli.Close()
+}
+
+// Ping reports whether the Docker daemon is reachable.
+func (m *Manager) Ping(ctx context.Context) error {
+	_, err := m.cli.Ping(ctx)
+	return err
+}
+
+// ConnectionStatus returns "connected" or "disconnected: <reason>", letting
+// the webserver distinguish "docker down" from "container absent".
+func (m *Manager) ConnectionStatus(ctx context.Context) string {
+	if err := m.Ping(ctx); err != nil {
+		return fmt.Sprintf("disconnected: %v", err)
+	}
+	return "connected"
+}
+
+// Ping reports whether the Docker daemon behind DefaultManager is reachable.
+func Ping(ctx context.Context) error {
+	if DefaultManager == nil {
+		return errNoDockerClient
+	}
+	return DefaultManager.Ping(ctx)
+}
+
+// ConnectionStatus returns DefaultManager's connection status.
+func ConnectionStatus(ctx context.Context) string {
+	if DefaultManager == nil {
+		return fmt.Sprintf("disconnected: %v", errNoDockerClient)
+	}
+	return DefaultManager.ConnectionStatus(ctx)
+}
+
 func Init(i int) {
 	Cstats = new(ContainerStats)
 	Cstats.Interval = i
 	Cstats.Stats = make(map[string]map[string]float64)
+	Cstats.History = make(map[string][]StatSample)
 	Cstats.StMu = new(sync.Mutex)
+	Cstats.streams = make(map[string]*streamHandle)
+
+	manager, err := NewManager()
+	if err != nil {
+		logger.Log.Errorf("Unable to open Docker session: %v", err)
+		return
+	}
+	DefaultManager = manager
 }
 
 func calculateCPUPercent(current, previous container.StatsResponse) float64 {
@@ -36,6 +139,11 @@ func calculateCPUPercent(current, previous container.StatsResponse) float64 {
 	systemDelta := float64(current.CPUStats.SystemUsage - previous.CPUStats.SystemUsage)
 	onlineCPUs := float64(current.CPUStats.OnlineCPUs)
 
+	// Older engines don't report OnlineCPUs, fall back to PercpuUsage length
+	if onlineCPUs <= 0.0 {
+		onlineCPUs = float64(len(current.CPUStats.CPUUsage.PercpuUsage))
+	}
+
 	// Avoid division by zero
 	if systemDelta <= 0.0 || onlineCPUs <= 0.0 {
 		return 0.0
@@ -45,176 +153,305 @@ func calculateCPUPercent(current, previous container.StatsResponse) float64 {
 	return (cpuDelta / systemDelta) * onlineCPUs * 100.0
 }
 
-func collectStats(cli *client.Client, containerSummary container.Summary, resultChan chan<- map[string]map[string]float64, wg *sync.WaitGroup) {
-	defer wg.Done()
+// calculateNetworkIO sums rx/tx bytes across all interfaces reported in v.Networks.
+func calculateNetworkIO(stats container.StatsResponse) (rx, tx float64) {
+	for _, net := range stats.Networks {
+		rx += float64(net.RxBytes)
+		tx += float64(net.TxBytes)
+	}
+	return rx, tx
+}
 
-	// Get initial stats
-	stats, err := cli.ContainerStats(context.Background(), containerSummary.ID, client.ContainerStatsOptions{Stream: false})
-	if err != nil {
-		resultChan <- map[string]map[string]float64{containerSummary.Names[0]: {"error": 1.0}}
-		return
+// calculateBlockIO sums read/write bytes out of BlkioStats.IoServiceBytesRecursive.
+func calculateBlockIO(stats container.StatsResponse) (read, write float64) {
+	for _, entry := range stats.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			read += float64(entry.Value)
+		case "write":
+			write += float64(entry.Value)
+		}
 	}
-	defer stats.Body.Close()
+	return read, write
+}
 
-	var prevStats container.StatsResponse
-	if err := json.NewDecoder(stats.Body).Decode(&prevStats); err != nil {
-		resultChan <- map[string]map[string]float64{containerSummary.Names[0]: {"error": 1.0}}
-		return
+// ratePerSecond converts a byte delta between two samples into a bytes-per-second rate.
+func ratePerSecond(current, previous, seconds float64) float64 {
+	if seconds <= 0 {
+		return 0.0
+	}
+	delta := current - previous
+	if delta < 0 {
+		return 0.0
 	}
+	return delta / seconds
+}
 
-	// Wait for 1 second
-	time.Sleep(time.Duration(Cstats.Interval) * time.Second)
+// pushSample appends to the ring buffer, trimming to statsHistorySize. Caller must hold StMu.
+func pushSample(containerName string, sample StatSample) {
+	history := append(Cstats.History[containerName], sample)
+	if len(history) > statsHistorySize {
+		history = history[len(history)-statsHistorySize:]
+	}
+	Cstats.History[containerName] = history
+}
 
-	// Get next stats
-	stats, err = cli.ContainerStats(context.Background(), containerSummary.ID, client.ContainerStatsOptions{Stream: false})
+// streamContainerStats keeps a persistent stats stream open for a container,
+// pushing a sample into its ring buffer on every update from the daemon
+// after the first, which only serves as the baseline for the deltas.
+// generation must match Cstats.streams[containerName] for this goroutine's
+// writes to be accepted; it stops writing (and returns) as soon as it
+// doesn't, which is how a cancelled or superseded goroutine is kept from
+// clobbering a newer stream or resurrecting an evicted container.
+func streamContainerStats(ctx context.Context, cli *client.Client, containerSummary container.Summary, generation int64) {
+	containerName := strings.TrimPrefix(containerSummary.Names[0], "/")
+
+	stream, err := cli.ContainerStats(ctx, containerSummary.ID, client.ContainerStatsOptions{Stream: true})
 	if err != nil {
-		resultChan <- map[string]map[string]float64{containerSummary.Names[0]: {"error": 1.0}}
+		logger.Log.Errorf("Unable to open stats stream for %s: %v", containerName, err)
 		return
 	}
-	defer stats.Body.Close()
+	defer stream.Body.Close()
 
-	var currentStats container.StatsResponse
-	if err := json.NewDecoder(stats.Body).Decode(&currentStats); err != nil {
-		resultChan <- map[string]map[string]float64{containerSummary.Names[0]: {"error": 1.0}}
-		return
-	}
+	decoder := json.NewDecoder(stream.Body)
 
-	// Calculate CPU percentage
-	cpuPercent := calculateCPUPercent(currentStats, prevStats)
+	var previous container.StatsResponse
+	var previousTimestamp time.Time
+	hasPrevious := false
 
-	// Calculate memory percentage
-	memUsage := float64(currentStats.MemoryStats.Usage)
-	// Substract the cache mem
-	if cache, ok := currentStats.MemoryStats.Stats["cache"]; ok {
-		memUsage -= float64(cache)
-	}
-	memLimit := float64(currentStats.MemoryStats.Limit)
-	memPercent := 0.0
-	if memLimit > 0 {
-		memPercent = (memUsage / memLimit) * 100.0
-	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
 
-	// Format results
-	containerName := strings.TrimPrefix(containerSummary.Names[0], "/")
-	resultChan <- map[string]map[string]float64{
-		containerName: {
-			"cpu": cpuPercent,
-			"mem": memPercent,
-		},
-	}
-}
+		var current container.StatsResponse
+		if err := decoder.Decode(&current); err != nil {
+			logger.Log.Errorf("Stats stream for %s ended: %v", containerName, err)
+			return
+		}
+		currentTimestamp := time.Now()
 
-func GetContainerLogs(containerName string) ([]string, error) {
-	var logLines []string
-	logLines = make([]string, 0)
+		if !hasPrevious {
+			previous = current
+			previousTimestamp = currentTimestamp
+			hasPrevious = true
+			continue
+		}
 
-	// Open Docker API
-	cli, err := client.New(client.FromEnv)
-	if err != nil {
-		logger.Log.Errorf("Unable to open Docker session: %v", err)
-		return logLines, err
-	}
-	defer cli.Close()
+		cpuPercent := calculateCPUPercent(current, previous)
 
-	containers, err := cli.ContainerList(context.Background(), client.ContainerListOptions{All: true})
-	if err != nil {
-		logger.Log.Errorf("Unable to list the containers: %v", err)
-		return logLines, err
-	}
+		memUsage := float64(current.MemoryStats.Usage)
+		if cache, ok := current.MemoryStats.Stats["cache"]; ok {
+			memUsage -= float64(cache)
+		}
+		memPercent := 0.0
+		if memLimit := float64(current.MemoryStats.Limit); memLimit > 0 {
+			memPercent = (memUsage / memLimit) * 100.0
+		}
 
-	var containerID string
-	for _, containerSummary := range containers.Items {
-		if slices.Contains(containerSummary.Names, "/"+containerName) {
-			containerID = containerSummary.ID
-			break
+		elapsed := currentTimestamp.Sub(previousTimestamp).Seconds()
+
+		prevRx, prevTx := calculateNetworkIO(previous)
+		curRx, curTx := calculateNetworkIO(current)
+		netRx := ratePerSecond(curRx, prevRx, elapsed)
+		netTx := ratePerSecond(curTx, prevTx, elapsed)
+
+		prevRead, prevWrite := calculateBlockIO(previous)
+		curRead, curWrite := calculateBlockIO(current)
+		blkRead := ratePerSecond(curRead, prevRead, elapsed)
+		blkWrite := ratePerSecond(curWrite, prevWrite, elapsed)
+
+		sample := StatSample{
+			Timestamp: time.Now(),
+			Cpu:       cpuPercent,
+			Mem:       memPercent,
+			NetRx:     netRx,
+			NetTx:     netTx,
+			BlkRead:   blkRead,
+			BlkWrite:  blkWrite,
+		}
+		metrics := map[string]float64{
+			"cpu":       cpuPercent,
+			"mem":       memPercent,
+			"net_rx":    netRx,
+			"net_tx":    netTx,
+			"blk_read":  blkRead,
+			"blk_write": blkWrite,
 		}
+
+		if !storeStatsSample(containerName, generation, metrics, sample) {
+			// Evicted, or superseded by a newer stream for this name; stop
+			// writing instead of resurrecting/clobbering its entry.
+			return
+		}
+
+		previous = current
+		previousTimestamp = currentTimestamp
 	}
+}
 
-	if containerID == "" {
-		logger.Log.Errorf("Container with name '%s' not found", containerName)
-		return logLines, fmt.Errorf("container with name '%s' not found", containerName)
+// storeStatsSample writes metrics/sample into Cstats.Stats/History for
+// containerName, but only if generation still matches the currently active
+// stream for that name. Returns false (without writing) if the container
+// was evicted or a newer stream has since taken over, which is what keeps a
+// cancelled or stale streamContainerStats goroutine from resurrecting an
+// evicted entry or clobbering a newer goroutine's samples.
+func storeStatsSample(containerName string, generation int64, metrics map[string]float64, sample StatSample) bool {
+	Cstats.StMu.Lock()
+	defer Cstats.StMu.Unlock()
+
+	if handle, ok := Cstats.streams[containerName]; !ok || handle.generation != generation {
+		return false
 	}
 
-	ctx := context.Background()
-	options := client.ContainerLogsOptions{
-		ShowStdout: true,
-		ShowStderr: true,
-		Tail:       fmt.Sprintf("%d", 200),
+	Cstats.Stats[containerName] = metrics
+	pushSample(containerName, sample)
+	return true
+}
+
+// startTrackedStream (re)registers containerSummary's name in Cstats.streams
+// under a fresh generation, cancelling whatever stream previously owned that
+// name, and launches streamContainerStats to own it. Used by both
+// StartStatsStream and WatchContainerLifecycle so the two entry points share
+// one tracking map instead of running disjoint mechanisms.
+func startTrackedStream(ctx context.Context, cli *client.Client, containerSummary container.Summary) {
+	containerName := strings.TrimPrefix(containerSummary.Names[0], "/")
+
+	Cstats.StMu.Lock()
+	if previous, ok := Cstats.streams[containerName]; ok {
+		previous.cancel()
 	}
+	Cstats.generation++
+	generation := Cstats.generation
+	streamCtx, cancel := context.WithCancel(ctx)
+	Cstats.streams[containerName] = &streamHandle{generation: generation, cancel: cancel}
+	Cstats.StMu.Unlock()
+
+	go streamContainerStats(streamCtx, cli, containerSummary, generation)
+}
 
-	logs, err := cli.ContainerLogs(ctx, containerID, options)
+// StartStatsStream opens a persistent stats stream per running container
+// instead of polling with a fresh HTTP call every Interval seconds.
+func (m *Manager) StartStatsStream(ctx context.Context) error {
+	containers, err := m.cli.ContainerList(ctx, client.ContainerListOptions{})
 	if err != nil {
-		logger.Log.Errorf("Unable to retrieve log for container %s: %v", containerName, err)
-		return logLines, err
+		logger.Log.Errorf("Error listing containers: %v\n", err)
+		return err
 	}
-	defer logs.Close()
 
-	scanner := bufio.NewScanner(logs)
-	for scanner.Scan() {
-		line := scanner.Bytes()
-		if len(line) > 8 {
-			line = line[8:] // Remove Docker log stream header
-		}
-		logLines = append(logLines, string(line))
+	for _, containerSummary := range containers.Items {
+		startTrackedStream(ctx, m.cli, containerSummary)
 	}
 
-	if err := scanner.Err(); err != nil {
-		logger.Log.Errorf("Unexpected error while collecting log fors container %s: %v", containerName, err)
-		return logLines, err
-	}
+	return nil
+}
 
-	return logLines, nil
+func StartStatsStream(ctx context.Context) error {
+	if DefaultManager == nil {
+		logger.Log.Errorf("Unable to start stats stream: %v", errNoDockerClient)
+		return errNoDockerClient
+	}
+	return DefaultManager.StartStatsStream(ctx)
 }
 
-func GetContainerStats() {
-	logger.Log.Debug("Start collecting container stats")
+// GetContainerLogs is a convenience wrapper around StreamContainerLogs for
+// callers that just want the last 200 lines as plain text.
+func (m *Manager) GetContainerLogs(containerName string) ([]string, error) {
+	logLines := make([]string, 0)
 
-	// Initialize Docker client
-	cli, err := client.New(client.FromEnv)
+	lines, err := m.StreamContainerLogs(context.Background(), containerName, LogOptions{Tail: "200"})
 	if err != nil {
-		logger.Log.Errorf("Error creating Docker client: %v\n", err)
-		return
+		logger.Log.Errorf("Unable to retrieve log for container %s: %v", containerName, err)
+		return logLines, err
 	}
 
-	// Get list of containers
-	containers, err := cli.ContainerList(context.Background(), client.ContainerListOptions{})
-	if err != nil {
-		logger.Log.Errorf("Error listing containers: %v\n", err)
-		return
+	for line := range lines {
+		logLines = append(logLines, line.Message)
 	}
 
-	// Set up synchronization
-	var wg sync.WaitGroup
-	resultChan := make(chan map[string]map[string]float64, len(containers.Items))
+	return logLines, nil
+}
 
-	// Collect stats in parallel
-	for _, containerSummary := range containers.Items {
-		wg.Add(1)
-		go collectStats(cli, containerSummary, resultChan, &wg)
+func GetContainerLogs(containerName string) ([]string, error) {
+	if DefaultManager == nil {
+		logger.Log.Errorf("Unable to retrieve log for container %s: %v", containerName, errNoDockerClient)
+		return nil, errNoDockerClient
 	}
+	return DefaultManager.GetContainerLogs(containerName)
+}
 
-	// Wait for all goroutines to finish
-	wg.Wait()
-	close(resultChan)
-
-	// Aggregate results
-	Cstats.StMu.Lock()
-	Cstats.Stats = make(map[string]map[string]float64)
-	for result := range resultChan {
-		maps.Copy(Cstats.Stats, result)
+// WatchContainerLifecycle subscribes to the Docker event stream and keeps
+// Cstats in sync with containers coming and going. It shares Cstats.streams
+// with StartStatsStream, so a container streamed from boot is torn down here
+// just like one started after the watcher began.
+func (m *Manager) WatchContainerLifecycle(ctx context.Context) error {
+	cli := m.cli
+
+	filterArgs := filters.NewArgs(
+		filters.Arg("type", string(events.ContainerEventType)),
+		filters.Arg("event", "die"),
+		filters.Arg("event", "stop"),
+		filters.Arg("event", "destroy"),
+		filters.Arg("event", "start"),
+		filters.Arg("event", "unpause"),
+	)
+
+	msgs, errs := cli.Events(ctx, events.ListOptions{Filters: filterArgs})
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errs:
+			if err != nil {
+				logger.Log.Errorf("Docker event stream error: %v", err)
+				return err
+			}
+		case msg := <-msgs:
+			containerName := strings.TrimPrefix(msg.Actor.Attributes["name"], "/")
+
+			switch string(msg.Action) {
+			case "die", "stop", "destroy":
+				Cstats.StMu.Lock()
+				if handle, ok := Cstats.streams[containerName]; ok {
+					handle.cancel()
+					delete(Cstats.streams, containerName)
+				}
+				delete(Cstats.Stats, containerName)
+				delete(Cstats.History, containerName)
+				Cstats.StMu.Unlock()
+
+				logger.Log.Infof("%s container is no longer running, evicted from stats", containerName)
+			case "start", "unpause":
+				// Docker can emit a duplicate start/unpause for the same
+				// container (e.g. a restart-policy bounce); don't launch a
+				// second collector for one already tracked as running.
+				Cstats.StMu.Lock()
+				_, alreadyTracked := Cstats.streams[containerName]
+				Cstats.StMu.Unlock()
+				if alreadyTracked {
+					continue
+				}
+
+				summary := container.Summary{ID: msg.Actor.ID, Names: []string{"/" + containerName}}
+				startTrackedStream(ctx, cli, summary)
+			}
+		}
 	}
-	Cstats.StMu.Unlock()
-	logger.Log.Debug("End of the container stats collection")
 }
 
-func ListContainers() []container.Summary {
-	cli, err := client.New(client.FromEnv)
-	if err != nil {
-		logger.Log.Errorf("Unable to open Docker session: %v", err)
+func WatchContainerLifecycle(ctx context.Context) error {
+	if DefaultManager == nil {
+		logger.Log.Errorf("Unable to watch container lifecycle: %v", errNoDockerClient)
+		return errNoDockerClient
 	}
-	defer cli.Close()
+	return DefaultManager.WatchContainerLifecycle(ctx)
+}
 
-	containers, err := cli.ContainerList(context.Background(), client.ContainerListOptions{})
+func (m *Manager) ListContainers() []container.Summary {
+	containers, err := m.cli.ContainerList(context.Background(), client.ContainerListOptions{})
 	if err != nil {
 		logger.Log.Errorf("Unable to list container state: %v", err)
 	}
@@ -222,19 +459,19 @@ func ListContainers() []container.Summary {
 	return containers.Items
 }
 
-func RestartContainer(name string) error {
-	timeout := 30
-
-	// Open Docker API
-	cli, err := client.New(client.FromEnv)
-	if err != nil {
-		logger.Log.Errorf("Unable to open Docker session: %v", err)
-		return err
+func ListContainers() []container.Summary {
+	if DefaultManager == nil {
+		logger.Log.Errorf("Unable to list containers: %v", errNoDockerClient)
+		return nil
 	}
-	defer cli.Close()
+	return DefaultManager.ListContainers()
+}
+
+func (m *Manager) RestartContainer(name string) error {
+	timeout := 30
 
 	// Restart container
-	_, err = cli.ContainerRestart(context.Background(), name, client.ContainerRestartOptions{Signal: "SIGTERM", Timeout: &timeout})
+	_, err := m.cli.ContainerRestart(context.Background(), name, client.ContainerRestartOptions{Signal: "SIGTERM", Timeout: &timeout})
 	if err != nil {
 		logger.Log.Errorf("Unable to restart %s container: %v", name, err)
 		return err
@@ -244,18 +481,18 @@ func RestartContainer(name string) error {
 
 }
 
-func StopContainer(name string) {
-	timeout := 30
-
-	// Open Docker API
-	cli, err := client.New(client.FromEnv)
-	if err != nil {
-		logger.Log.Errorf("Unable to open Docker session: %v", err)
-		return
+func RestartContainer(name string) error {
+	if DefaultManager == nil {
+		logger.Log.Errorf("Unable to restart %s container: %v", name, errNoDockerClient)
+		return errNoDockerClient
 	}
-	defer cli.Close()
+	return DefaultManager.RestartContainer(name)
+}
+
+func (m *Manager) StopContainer(name string) {
+	timeout := 30
 
-	_, err = cli.ContainerStop(context.Background(), name, client.ContainerStopOptions{Signal: "SIGTERM", Timeout: &timeout})
+	_, err := m.cli.ContainerStop(context.Background(), name, client.ContainerStopOptions{Signal: "SIGTERM", Timeout: &timeout})
 	if err != nil {
 		logger.Log.Errorf("Unable to stop %s container: %v", name, err)
 		return
@@ -264,18 +501,18 @@ func StopContainer(name string) {
 
 }
 
-func GetVersionLabel(name string) string {
-
-	// Open Docker API
-	cli, err := client.New(client.FromEnv)
-	if err != nil {
-		logger.Log.Errorf("Unable to open Docker session: %v", err)
-		return "N/A"
+func StopContainer(name string) {
+	if DefaultManager == nil {
+		logger.Log.Errorf("Unable to stop %s container: %v", name, errNoDockerClient)
+		return
 	}
-	defer cli.Close()
+	DefaultManager.StopContainer(name)
+}
+
+func (m *Manager) GetVersionLabel(name string) string {
 
 	// Get the image details using the Docker API
-	imageInspect, err := cli.ImageInspect(context.Background(), name)
+	imageInspect, err := m.cli.ImageInspect(context.Background(), name)
 	if err != nil {
 		logger.Log.Errorf("Unable to retrieve Docker %s inspect data: %v", name, err)
 		return "N/A"
@@ -294,3 +531,11 @@ func GetVersionLabel(name string) string {
 	return version
 
 }
+
+func GetVersionLabel(name string) string {
+	if DefaultManager == nil {
+		logger.Log.Errorf("Unable to retrieve Docker %s version: %v", name, errNoDockerClient)
+		return "N/A"
+	}
+	return DefaultManager.GetVersionLabel(name)
+}
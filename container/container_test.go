@@ -0,0 +1,188 @@
+package container
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/moby/moby/api/types/container"
+)
+
+func TestCalculateCPUPercent(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  container.StatsResponse
+		previous container.StatsResponse
+		want     float64
+	}{
+		{
+			name: "uses OnlineCPUs when reported",
+			current: container.StatsResponse{
+				CPUStats: container.CPUStats{
+					CPUUsage:    container.CPUUsage{TotalUsage: 400},
+					SystemUsage: 1000,
+					OnlineCPUs:  2,
+				},
+			},
+			previous: container.StatsResponse{
+				CPUStats: container.CPUStats{
+					CPUUsage:    container.CPUUsage{TotalUsage: 200},
+					SystemUsage: 500,
+				},
+			},
+			want: 80.0,
+		},
+		{
+			name: "falls back to PercpuUsage length when OnlineCPUs is 0",
+			current: container.StatsResponse{
+				CPUStats: container.CPUStats{
+					CPUUsage:    container.CPUUsage{TotalUsage: 400, PercpuUsage: []uint64{0, 0}},
+					SystemUsage: 1000,
+					OnlineCPUs:  0,
+				},
+			},
+			previous: container.StatsResponse{
+				CPUStats: container.CPUStats{
+					CPUUsage:    container.CPUUsage{TotalUsage: 200},
+					SystemUsage: 500,
+				},
+			},
+			want: 80.0,
+		},
+		{
+			name: "zero system delta avoids division by zero",
+			current: container.StatsResponse{
+				CPUStats: container.CPUStats{
+					CPUUsage:    container.CPUUsage{TotalUsage: 400},
+					SystemUsage: 500,
+					OnlineCPUs:  2,
+				},
+			},
+			previous: container.StatsResponse{
+				CPUStats: container.CPUStats{
+					CPUUsage:    container.CPUUsage{TotalUsage: 200},
+					SystemUsage: 500,
+				},
+			},
+			want: 0.0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calculateCPUPercent(tt.current, tt.previous); got != tt.want {
+				t.Errorf("calculateCPUPercent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateNetworkIO(t *testing.T) {
+	stats := container.StatsResponse{
+		Networks: map[string]container.NetworkStats{
+			"eth0": {RxBytes: 100, TxBytes: 50},
+			"eth1": {RxBytes: 10, TxBytes: 5},
+		},
+	}
+
+	rx, tx := calculateNetworkIO(stats)
+	if rx != 110 {
+		t.Errorf("rx = %v, want 110", rx)
+	}
+	if tx != 55 {
+		t.Errorf("tx = %v, want 55", tx)
+	}
+}
+
+func TestCalculateBlockIO(t *testing.T) {
+	stats := container.StatsResponse{
+		BlkioStats: container.BlkioStats{
+			IoServiceBytesRecursive: []container.BlkioStatEntry{
+				{Op: "Read", Value: 100},
+				{Op: "Write", Value: 40},
+				{Op: "read", Value: 10},
+				{Op: "Total", Value: 999},
+			},
+		},
+	}
+
+	read, write := calculateBlockIO(stats)
+	if read != 110 {
+		t.Errorf("read = %v, want 110", read)
+	}
+	if write != 40 {
+		t.Errorf("write = %v, want 40", write)
+	}
+}
+
+func TestRatePerSecond(t *testing.T) {
+	tests := []struct {
+		name                       string
+		current, previous, seconds float64
+		want                       float64
+	}{
+		{"normal rate", 300, 100, 2, 100},
+		{"zero seconds", 300, 100, 0, 0},
+		{"negative seconds", 300, 100, -1, 0},
+		{"counter reset treated as no rate", 50, 100, 2, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ratePerSecond(tt.current, tt.previous, tt.seconds); got != tt.want {
+				t.Errorf("ratePerSecond() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPushSample(t *testing.T) {
+	Cstats = &ContainerStats{History: make(map[string][]StatSample)}
+
+	for i := 0; i < statsHistorySize+5; i++ {
+		pushSample("web", StatSample{Cpu: float64(i)})
+	}
+
+	history := Cstats.History["web"]
+	if len(history) != statsHistorySize {
+		t.Fatalf("len(history) = %d, want %d", len(history), statsHistorySize)
+	}
+	if history[0].Cpu != 5 {
+		t.Errorf("oldest retained sample Cpu = %v, want 5 (ring buffer should have dropped the first 5)", history[0].Cpu)
+	}
+	if last := history[len(history)-1]; last.Cpu != float64(statsHistorySize+4) {
+		t.Errorf("newest sample Cpu = %v, want %v", last.Cpu, statsHistorySize+4)
+	}
+}
+
+func TestStoreStatsSample(t *testing.T) {
+	Cstats = &ContainerStats{
+		Stats:   make(map[string]map[string]float64),
+		History: make(map[string][]StatSample),
+		streams: make(map[string]*streamHandle),
+		StMu:    new(sync.Mutex),
+	}
+	Cstats.streams["web"] = &streamHandle{generation: 2}
+
+	if ok := storeStatsSample("web", 2, map[string]float64{"cpu": 1}, StatSample{Cpu: 1}); !ok {
+		t.Fatal("storeStatsSample() = false, want true for the current generation")
+	}
+	if _, ok := Cstats.Stats["web"]; !ok {
+		t.Error("Stats[\"web\"] not written for the current generation")
+	}
+
+	// A stale goroutine from an older generation (e.g. cancelled and
+	// superseded by a restart) must not be able to write.
+	if ok := storeStatsSample("web", 1, map[string]float64{"cpu": 99}, StatSample{Cpu: 99}); ok {
+		t.Error("storeStatsSample() = true, want false for a superseded generation")
+	}
+	if got := Cstats.Stats["web"]["cpu"]; got != 1 {
+		t.Errorf("Stats[\"web\"][\"cpu\"] = %v, want 1 (stale write must not have applied)", got)
+	}
+
+	// An evicted container (die/stop/destroy removed its stream handle)
+	// must reject writes even from what was its current generation.
+	delete(Cstats.streams, "web")
+	if ok := storeStatsSample("web", 2, map[string]float64{"cpu": 5}, StatSample{Cpu: 5}); ok {
+		t.Error("storeStatsSample() = true, want false once the container has been evicted")
+	}
+}
@@ -0,0 +1,179 @@
+package container
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"jtso/logger"
+	"strings"
+	"time"
+
+	"github.com/moby/moby/client"
+)
+
+// LogStream identifies which of a container's two multiplexed log streams a
+// LogLine came from.
+type LogStream string
+
+const (
+	LogStreamStdout LogStream = "stdout"
+	LogStreamStderr LogStream = "stderr"
+)
+
+// LogLine is a single parsed entry out of a container's log stream.
+type LogLine struct {
+	Stream    LogStream
+	Timestamp time.Time
+	Message   string
+}
+
+// LogOptions controls StreamContainerLogs, mirroring the options exposed by
+// the Docker engine's own logs endpoint.
+type LogOptions struct {
+	Follow bool
+	Since  string
+	Until  string
+	Tail   string
+}
+
+// maxLogFrameSize caps the size field read from a multiplexed log frame
+// header. ContainerLogs frames are never anywhere near this large; a bigger
+// value almost certainly means the reader lost sync with the stream (e.g. a
+// TTY container whose output isn't actually framed), so we bail out instead
+// of trying to allocate it.
+const maxLogFrameSize = 16 * 1024 * 1024
+
+// StreamContainerLogs streams a container's logs as LogLines. For a
+// non-TTY container, each frame is parsed from Docker's 8-byte multiplexed
+// stream header ([STREAM_TYPE, 0, 0, 0, SIZE_BE_32]) instead of being split
+// on newlines, so stdout/stderr stay distinguished and the frame boundaries
+// survive multi-line messages. A container started with a TTY has no such
+// framing (stdout/stderr are already combined by the daemon), so those are
+// read line by line and reported as LogStreamStdout. The returned channel is
+// closed when ctx is cancelled, when Follow is false and the stream reaches
+// EOF, or on a read error.
+func (m *Manager) StreamContainerLogs(ctx context.Context, name string, opts LogOptions) (<-chan LogLine, error) {
+	inspect, err := m.cli.ContainerInspect(ctx, name)
+	if err != nil {
+		logger.Log.Errorf("Unable to inspect %s container: %v", name, err)
+		return nil, err
+	}
+	tty := inspect.Config != nil && inspect.Config.Tty
+
+	tail := opts.Tail
+	if tail == "" {
+		tail = "200"
+	}
+
+	logs, err := m.cli.ContainerLogs(ctx, name, client.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Timestamps: true,
+		Follow:     opts.Follow,
+		Since:      opts.Since,
+		Until:      opts.Until,
+		Tail:       tail,
+	})
+	if err != nil {
+		logger.Log.Errorf("Unable to open log stream for %s: %v", name, err)
+		return nil, err
+	}
+
+	lines := make(chan LogLine)
+
+	if tty {
+		go streamTtyLogLines(ctx, logs, name, lines)
+	} else {
+		go streamMultiplexedLogLines(ctx, logs, name, lines)
+	}
+
+	return lines, nil
+}
+
+// streamTtyLogLines reads a TTY container's raw, unframed log stream one
+// line at a time.
+func streamTtyLogLines(ctx context.Context, logs io.ReadCloser, name string, lines chan<- LogLine) {
+	defer close(lines)
+	defer logs.Close()
+
+	scanner := bufio.NewScanner(logs)
+	for scanner.Scan() {
+		timestamp, message := splitLogTimestamp(scanner.Text())
+
+		select {
+		case lines <- LogLine{Stream: LogStreamStdout, Timestamp: timestamp, Message: message}:
+		case <-ctx.Done():
+			return
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		logger.Log.Errorf("Error reading log stream for %s: %v", name, err)
+	}
+}
+
+// streamMultiplexedLogLines reads a non-TTY container's log stream frame by
+// frame using Docker's multiplexed stream header.
+func streamMultiplexedLogLines(ctx context.Context, logs io.ReadCloser, name string, lines chan<- LogLine) {
+	defer close(lines)
+	defer logs.Close()
+
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(logs, header); err != nil {
+			if err != io.EOF {
+				logger.Log.Errorf("Error reading log stream header for %s: %v", name, err)
+			}
+			return
+		}
+
+		streamType := header[0]
+		size := binary.BigEndian.Uint32(header[4:8])
+		if size > maxLogFrameSize {
+			logger.Log.Errorf("Log stream frame for %s claims %d bytes, exceeding the %d byte limit; stream is likely out of sync", name, size, maxLogFrameSize)
+			return
+		}
+
+		payload := make([]byte, size)
+		if _, err := io.ReadFull(logs, payload); err != nil {
+			logger.Log.Errorf("Error reading log stream frame for %s: %v", name, err)
+			return
+		}
+
+		stream := LogStreamStdout
+		if streamType == 2 {
+			stream = LogStreamStderr
+		}
+
+		timestamp, message := splitLogTimestamp(string(payload))
+
+		select {
+		case lines <- LogLine{Stream: stream, Timestamp: timestamp, Message: message}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func StreamContainerLogs(ctx context.Context, name string, opts LogOptions) (<-chan LogLine, error) {
+	if DefaultManager == nil {
+		logger.Log.Errorf("Unable to open log stream for %s: %v", name, errNoDockerClient)
+		return nil, errNoDockerClient
+	}
+	return DefaultManager.StreamContainerLogs(ctx, name, opts)
+}
+
+// splitLogTimestamp pulls off the RFC3339Nano timestamp Docker prefixes onto
+// each line when Timestamps is requested, leaving the raw message behind.
+func splitLogTimestamp(raw string) (time.Time, string) {
+	raw = strings.TrimRight(raw, "\n")
+	parts := strings.SplitN(raw, " ", 2)
+	if len(parts) != 2 {
+		return time.Time{}, raw
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return time.Time{}, raw
+	}
+	return timestamp, parts[1]
+}
@@ -0,0 +1,119 @@
+package container
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestSplitLogTimestamp(t *testing.T) {
+	tests := []struct {
+		name          string
+		raw           string
+		wantTimestamp time.Time
+		wantMessage   string
+	}{
+		{
+			name:          "timestamp and message",
+			raw:           "2024-01-02T03:04:05.000000000Z hello world\n",
+			wantTimestamp: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			wantMessage:   "hello world",
+		},
+		{
+			name:          "no timestamp prefix",
+			raw:           "hello world",
+			wantTimestamp: time.Time{},
+			wantMessage:   "hello world",
+		},
+		{
+			name:          "unparseable timestamp",
+			raw:           "not-a-timestamp hello",
+			wantTimestamp: time.Time{},
+			wantMessage:   "not-a-timestamp hello",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotTimestamp, gotMessage := splitLogTimestamp(tt.raw)
+			if !gotTimestamp.Equal(tt.wantTimestamp) {
+				t.Errorf("timestamp = %v, want %v", gotTimestamp, tt.wantTimestamp)
+			}
+			if gotMessage != tt.wantMessage {
+				t.Errorf("message = %q, want %q", gotMessage, tt.wantMessage)
+			}
+		})
+	}
+}
+
+// multiplexedFrame builds one Docker log frame: [STREAM_TYPE,0,0,0,SIZE_BE_32] + payload.
+func multiplexedFrame(streamType byte, payload string) []byte {
+	header := make([]byte, 8)
+	header[0] = streamType
+	binary.BigEndian.PutUint32(header[4:8], uint32(len(payload)))
+	return append(header, []byte(payload)...)
+}
+
+func TestStreamMultiplexedLogLines(t *testing.T) {
+	raw := append(
+		multiplexedFrame(1, "2024-01-02T03:04:05.000000000Z stdout line\n"),
+		multiplexedFrame(2, "2024-01-02T03:04:06.000000000Z stderr line\n")...,
+	)
+	logs := io.NopCloser(bytes.NewReader(raw))
+
+	lines := make(chan LogLine)
+	go streamMultiplexedLogLines(context.Background(), logs, "test", lines)
+
+	first := <-lines
+	if first.Stream != LogStreamStdout || first.Message != "stdout line" {
+		t.Errorf("first line = %+v, want stdout/\"stdout line\"", first)
+	}
+
+	second := <-lines
+	if second.Stream != LogStreamStderr || second.Message != "stderr line" {
+		t.Errorf("second line = %+v, want stderr/\"stderr line\"", second)
+	}
+
+	if _, ok := <-lines; ok {
+		t.Error("expected channel to be closed at EOF")
+	}
+}
+
+func TestStreamMultiplexedLogLinesRejectsOversizedFrame(t *testing.T) {
+	header := make([]byte, 8)
+	header[0] = 1
+	binary.BigEndian.PutUint32(header[4:8], maxLogFrameSize+1)
+	logs := io.NopCloser(bytes.NewReader(header))
+
+	lines := make(chan LogLine)
+	go streamMultiplexedLogLines(context.Background(), logs, "test", lines)
+
+	if _, ok := <-lines; ok {
+		t.Error("expected channel to be closed without emitting a line for an oversized frame")
+	}
+}
+
+func TestStreamTtyLogLines(t *testing.T) {
+	raw := "2024-01-02T03:04:05.000000000Z first\n2024-01-02T03:04:06.000000000Z second\n"
+	logs := io.NopCloser(bytes.NewReader([]byte(raw)))
+
+	lines := make(chan LogLine)
+	go streamTtyLogLines(context.Background(), logs, "test", lines)
+
+	first := <-lines
+	if first.Stream != LogStreamStdout || first.Message != "first" {
+		t.Errorf("first line = %+v, want stdout/\"first\"", first)
+	}
+
+	second := <-lines
+	if second.Stream != LogStreamStdout || second.Message != "second" {
+		t.Errorf("second line = %+v, want stdout/\"second\"", second)
+	}
+
+	if _, ok := <-lines; ok {
+		t.Error("expected channel to be closed at EOF")
+	}
+}